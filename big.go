@@ -0,0 +1,235 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"reflect"
+)
+
+const (
+	maxBigIntBitLen = 256
+
+	bigIntLenLabel   = "bigintlen"
+	bigIntWordsLabel = "bigintwords"
+
+	defaultBigFloatPrec = 64
+
+	bigWordBits = bits.UintSize
+)
+
+var (
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+	bigRatType   = reflect.TypeOf((*big.Rat)(nil))
+
+	bigFloatSpecials = []float64{
+		0,
+		math.Copysign(0, -1),
+		math.SmallestNonzeroFloat64,
+		math.MaxFloat64,
+		-math.MaxFloat64,
+		math.Inf(1),
+		math.Inf(-1),
+	}
+)
+
+// BigInts generates arbitrary *big.Int values, biased toward small
+// magnitudes (like Ints()) so that shrinking converges quickly.
+func BigInts() *Generator {
+	return newGenerator(&bigIntGen{})
+}
+
+// BigIntsRange is like BigInts(), but only generates values n with
+// min <= n <= max. Both bounds are inclusive and must not be nil.
+func BigIntsRange(min *big.Int, max *big.Int) *Generator {
+	assertf(min != nil && max != nil, "min and max should not be nil")
+	assertf(min.Cmp(max) <= 0, "invalid range [%v, %v]", min, max)
+
+	return newGenerator(&bigIntGen{min: min, max: max})
+}
+
+type bigIntGen struct {
+	min *big.Int
+	max *big.Int
+}
+
+func (g *bigIntGen) String() string {
+	if g.min != nil {
+		return fmt.Sprintf("BigIntsRange(%v, %v)", g.min, g.max)
+	}
+	return "BigInts()"
+}
+
+func (g *bigIntGen) type_() reflect.Type {
+	return bigIntType
+}
+
+func (g *bigIntGen) value(s bitStream) Value {
+	if g.min != nil {
+		return g.rangeValue(s)
+	}
+
+	i := s.beginGroup(bigIntLenLabel, false)
+	bitLen := int(genIntRange(s, 0, maxBigIntBitLen, true))
+	s.endGroup(i, false)
+
+	neg := flipBiasedCoin(s, 0.5)
+
+	j := s.beginGroup(bigIntWordsLabel, false)
+	words := genBigWords(s, bitLen)
+	s.endGroup(j, false)
+
+	v := new(big.Int).SetBits(words)
+	if neg {
+		v.Neg(v)
+	}
+	return v
+}
+
+// rangeValue draws a value in [min, max] by generating diff.BitLen() random
+// bits and reducing modulo (diff+1). This is slightly biased toward the low
+// end of the range for non-power-of-two diffs, which is an acceptable
+// trade-off for the convergence benefit of using the shared bit-length
+// machinery instead of a rejection loop.
+func (g *bigIntGen) rangeValue(s bitStream) Value {
+	diff := new(big.Int).Sub(g.max, g.min)
+	if diff.Sign() == 0 {
+		return new(big.Int).Set(g.min)
+	}
+
+	i := s.beginGroup(bigIntWordsLabel, false)
+	words := genBigWords(s, diff.BitLen())
+	s.endGroup(i, false)
+
+	v := new(big.Int).SetBits(words)
+	v.Mod(v, new(big.Int).Add(diff, big.NewInt(1)))
+	v.Add(v, g.min)
+	return v
+}
+
+// genBigWords draws bitLen random bits, biased toward small magnitudes via
+// the caller's choice of bitLen, and packs them into big.Word-sized limbs
+// suitable for big.Int.SetBits.
+func genBigWords(s bitStream, bitLen int) []big.Word {
+	if bitLen <= 0 {
+		return nil
+	}
+
+	n := (bitLen + bigWordBits - 1) / bigWordBits
+	words := make([]big.Word, n)
+
+	for i := 0; i < n-1; i++ {
+		words[i] = big.Word(genUintRange(s, 0, maxUintBits(bigWordBits), false))
+	}
+	topBits := bitLen - (n-1)*bigWordBits
+	words[n-1] = big.Word(genUintRange(s, 0, maxUintBits(topBits), false))
+
+	return words
+}
+
+func maxUintBits(n int) uint64 {
+	if n >= 64 {
+		return math.MaxUint64
+	}
+	return uint64(1)<<uint(n) - 1
+}
+
+// BigFloats generates arbitrary *big.Float values at the default precision
+// of 64 bits of mantissa, including targeted draws of zero and +-Inf.
+func BigFloats() *Generator {
+	return BigFloatsPrec(defaultBigFloatPrec)
+}
+
+// BigFloatsPrec is like BigFloats(), but generates values with the given
+// mantissa precision.
+func BigFloatsPrec(prec uint) *Generator {
+	return newGenerator(&bigFloatGen{prec: prec, min: -math.MaxFloat64, max: math.MaxFloat64})
+}
+
+// BigFloatsRange is like BigFloatsPrec(), but only draws values in
+// [min, max] (except for the targeted +-Inf draws). Each drawn float64 is
+// then rounded to prec bits, so for prec below 53 a value near a bound can
+// round to just outside [min, max].
+func BigFloatsRange(min float64, max float64, prec uint) *Generator {
+	assertf(min == min, "min should not be a NaN")
+	assertf(max == max, "max should not be a NaN")
+	assertf(min <= max, "invalid range [%v, %v]", min, max)
+
+	return newGenerator(&bigFloatGen{prec: prec, min: min, max: max, ranged: true})
+}
+
+type bigFloatGen struct {
+	prec   uint
+	min    float64
+	max    float64
+	ranged bool
+}
+
+func (g *bigFloatGen) String() string {
+	if g.ranged {
+		return fmt.Sprintf("BigFloatsRange(%g, %g, %d)", g.min, g.max, g.prec)
+	}
+	return fmt.Sprintf("BigFloatsPrec(%d)", g.prec)
+}
+
+func (g *bigFloatGen) type_() reflect.Type {
+	return bigFloatType
+}
+
+func (g *bigFloatGen) value(s bitStream) Value {
+	if cands := g.specialCandidates(); len(cands) > 0 && flipBiasedCoin(s, 0.1) {
+		i := s.beginGroup(floatSpecialLabel, false)
+		idx := genIntRange(s, 0, int64(len(cands)-1), true)
+		s.endGroup(i, false)
+
+		return new(big.Float).SetPrec(g.prec).SetFloat64(cands[idx])
+	}
+
+	f := genFloatRange(s, g.min, g.max, float64ExpBits, float64SignifBits)
+	return new(big.Float).SetPrec(g.prec).SetFloat64(f)
+}
+
+// specialCandidates returns the entries of bigFloatSpecials that satisfy
+// g's bounds, except +-Inf which is always included since it is allowed to
+// fall outside [min, max] by contract.
+func (g *bigFloatGen) specialCandidates() []float64 {
+	cands := make([]float64, 0, len(bigFloatSpecials))
+	for _, f := range bigFloatSpecials {
+		if math.IsInf(f, 0) || (f >= g.min && f <= g.max) {
+			cands = append(cands, f)
+		}
+	}
+	return cands
+}
+
+// BigRats generates arbitrary *big.Rat values by combining two BigInts()
+// draws into a numerator and a (never-zero) denominator.
+func BigRats() *Generator {
+	return newGenerator(&bigRatGen{})
+}
+
+type bigRatGen struct{}
+
+func (g *bigRatGen) String() string {
+	return "BigRats()"
+}
+
+func (g *bigRatGen) type_() reflect.Type {
+	return bigRatType
+}
+
+func (g *bigRatGen) value(s bitStream) Value {
+	num := (&bigIntGen{}).value(s).(*big.Int)
+	den := (&bigIntGen{}).value(s).(*big.Int)
+	if den.Sign() == 0 {
+		den = big.NewInt(1)
+	}
+
+	return new(big.Rat).SetFrac(num, den)
+}
@@ -0,0 +1,58 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid_test
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/flyingmutant/rapid"
+)
+
+func TestBigIntsRangeBounds(t *testing.T) {
+	min := big.NewInt(-1000)
+	max := big.NewInt(1000)
+	g := BigIntsRange(min, max)
+
+	Check(t, func(t *T, v *big.Int) {
+		if v.Cmp(min) < 0 || v.Cmp(max) > 0 {
+			t.Fatalf("got %v outside of [%v, %v]", v, min, max)
+		}
+	}, g)
+}
+
+func TestBigIntsFilter(t *testing.T) {
+	g := BigInts().Filter(func(v *big.Int) bool { return v.Sign() > 0 })
+
+	Check(t, func(t *T, v *big.Int) {
+		if v.Sign() <= 0 {
+			t.Fatalf("got non-positive %v", v)
+		}
+	}, g)
+}
+
+func TestBigFloatsRangeRespectsBounds(t *testing.T) {
+	g := BigFloatsRange(1, 10, 53)
+
+	Check(t, func(t *T, v *big.Float) {
+		if v.IsInf() {
+			return
+		}
+		f, _ := v.Float64()
+		if f < 1 || f > 10 {
+			t.Fatalf("got %v outside of [1, 10]", v)
+		}
+	}, g)
+}
+
+func TestBigRatsDenominatorNonZero(t *testing.T) {
+	g := BigRats()
+
+	Check(t, func(t *T, v *big.Rat) {
+		if v.Denom().Sign() == 0 {
+			t.Fatalf("got zero denominator")
+		}
+	}, g)
+}
@@ -17,13 +17,63 @@ const (
 	float64ExpBits    = 11
 	float64SignifBits = 52
 
-	floatExpLabel    = "floatexp"
-	floatSignifLabel = "floatsignif"
+	floatExpLabel     = "floatexp"
+	floatSignifLabel  = "floatsignif"
+	floatSpecialLabel = "floatspecial"
+
+	// defaultSpecialsFrac is the fraction of Float32sAny()/Float64sAny() draws
+	// that come from the curated special-value set rather than genFloatRange.
+	defaultSpecialsFrac = 0.25
 )
 
 var (
 	float32Type = reflect.TypeOf(float32(0))
 	float64Type = reflect.TypeOf(float64(0))
+
+	// float64Specials is ordered so that the bitstream shrinker, which biases
+	// toward lower indices, prefers +0 over -0, subnormals over normals, and
+	// leaves the NaN payloads (grouped at the end) for last. Within that
+	// group, a signaling NaN, a negative quiet NaN and a quiet NaN with a
+	// non-canonical payload come before the canonical quiet NaN produced by
+	// math.NaN(), so that drawing "some NaN" exercises more than one bit
+	// pattern instead of always handing callers the exact same one.
+	float64Specials = []float64{
+		0,
+		math.Copysign(0, -1),
+		math.SmallestNonzeroFloat64,
+		math.Float64frombits(1<<52 - 1), // largest subnormal
+		math.Float64frombits(1 << 52),   // smallest normal
+		1,
+		-1,
+		math.Pi,
+		math.E,
+		math.MaxFloat64,
+		math.Inf(1),
+		math.Inf(-1),
+		math.Float64frombits(0x7ff0000000000001), // signaling NaN
+		math.Float64frombits(0xfff8000000000000), // negative quiet NaN
+		math.Float64frombits(0x7ff8000000000001), // quiet NaN, non-canonical payload
+		math.NaN(),
+	}
+
+	float32Specials = []float64{
+		0,
+		math.Copysign(0, -1),
+		float64(math.SmallestNonzeroFloat32),
+		float64(math.Float32frombits(1<<23 - 1)), // largest subnormal
+		float64(math.Float32frombits(1 << 23)),   // smallest normal
+		1,
+		-1,
+		float64(float32(math.Pi)),
+		float64(float32(math.E)),
+		float64(math.MaxFloat32),
+		math.Inf(1),
+		math.Inf(-1),
+		float64(math.Float32frombits(0x7f800001)), // signaling NaN
+		float64(math.Float32frombits(0xffc00000)), // negative quiet NaN
+		float64(math.Float32frombits(0x7fc00001)), // quiet NaN, non-canonical payload
+		math.NaN(),
+	}
 )
 
 func Float32s() *Generator {
@@ -54,6 +104,57 @@ func Float32sRange(min float32, max float32) *Generator {
 	})
 }
 
+// Float32sAny is like Float32s(), but also generates NaN, +-Inf and the other
+// IEEE-754 boundary values (+-0, subnormals, MaxFloat32) via a biased special
+// value draw, so that code paths that only trigger on these values get
+// exercised without the caller hunting for them by hand. Use
+// Float32sWithConfig to control how often specials are drawn.
+func Float32sAny() *Generator {
+	return Float32sWithConfig(FloatsConfig{
+		Min:          -math.MaxFloat32,
+		Max:          math.MaxFloat32,
+		AllowNaN:     true,
+		AllowInf:     true,
+		SpecialsFrac: defaultSpecialsFrac,
+	})
+}
+
+// FloatsConfig configures Float32sWithConfig/Float64sWithConfig: the
+// inclusive range to draw from, whether NaN/+-Inf may be produced, and what
+// fraction of draws are biased toward the curated special-value set
+// (+-0, subnormals, MaxFloat, 1, -1, pi, e, +-Inf, NaN) rather than drawn
+// via the usual genFloatRange machinery. SpecialsFrac of 0 disables
+// special-value biasing entirely.
+type FloatsConfig struct {
+	Min          float64
+	Max          float64
+	AllowNaN     bool
+	AllowInf     bool
+	SpecialsFrac float64
+}
+
+// Float32sWithConfig is Float32sRange(), with full control over NaN/+-Inf
+// generation and how often special values are drawn, via cfg.
+func Float32sWithConfig(cfg FloatsConfig) *Generator {
+	assertf(cfg.Min == cfg.Min, "Min should not be a NaN")
+	assertf(cfg.Max == cfg.Max, "Max should not be a NaN")
+	assertf(cfg.Min <= cfg.Max, "invalid range [%v, %v]", cfg.Min, cfg.Max)
+	assertf(cfg.SpecialsFrac >= 0 && cfg.SpecialsFrac <= 1, "SpecialsFrac should be in [0, 1], got %v", cfg.SpecialsFrac)
+
+	return newGenerator(&floatGen{
+		typ:          float32Type,
+		expBits:      float32ExpBits,
+		signifBits:   float32SignifBits,
+		min:          cfg.Min,
+		max:          cfg.Max,
+		minVal:       -math.MaxFloat32,
+		maxVal:       math.MaxFloat32,
+		allowNaN:     cfg.AllowNaN,
+		allowInf:     cfg.AllowInf,
+		specialsFrac: cfg.SpecialsFrac,
+	})
+}
+
 func Float64s() *Generator {
 	return Float64sRange(-math.MaxFloat64, math.MaxFloat64)
 }
@@ -82,6 +183,57 @@ func Float64sRange(min float64, max float64) *Generator {
 	})
 }
 
+// Float64sAny is like Float64s(), but also generates NaN, +-Inf and the other
+// IEEE-754 boundary values (+-0, subnormals, MaxFloat64) via a biased special
+// value draw, so that code paths that only trigger on these values get
+// exercised without the caller hunting for them by hand. Use
+// Float64sWithConfig to control how often specials are drawn.
+func Float64sAny() *Generator {
+	return Float64sWithConfig(FloatsConfig{
+		Min:          -math.MaxFloat64,
+		Max:          math.MaxFloat64,
+		AllowNaN:     true,
+		AllowInf:     true,
+		SpecialsFrac: defaultSpecialsFrac,
+	})
+}
+
+// Float64sWithSpecials is Float64sRange(), with explicit control over whether
+// NaN and +-Inf may be produced, for tests that check IEEE-754 compliance
+// and need those values without widening the finite range. It draws
+// specials at the default rate; use Float64sWithConfig to tune that rate.
+func Float64sWithSpecials(min float64, max float64, allowNaN bool, allowInf bool) *Generator {
+	return Float64sWithConfig(FloatsConfig{
+		Min:          min,
+		Max:          max,
+		AllowNaN:     allowNaN,
+		AllowInf:     allowInf,
+		SpecialsFrac: defaultSpecialsFrac,
+	})
+}
+
+// Float64sWithConfig is Float64sRange(), with full control over NaN/+-Inf
+// generation and how often special values are drawn, via cfg.
+func Float64sWithConfig(cfg FloatsConfig) *Generator {
+	assertf(cfg.Min == cfg.Min, "Min should not be a NaN")
+	assertf(cfg.Max == cfg.Max, "Max should not be a NaN")
+	assertf(cfg.Min <= cfg.Max, "invalid range [%v, %v]", cfg.Min, cfg.Max)
+	assertf(cfg.SpecialsFrac >= 0 && cfg.SpecialsFrac <= 1, "SpecialsFrac should be in [0, 1], got %v", cfg.SpecialsFrac)
+
+	return newGenerator(&floatGen{
+		typ:          float64Type,
+		expBits:      float64ExpBits,
+		signifBits:   float64SignifBits,
+		min:          cfg.Min,
+		max:          cfg.Max,
+		minVal:       -math.MaxFloat64,
+		maxVal:       math.MaxFloat64,
+		allowNaN:     cfg.AllowNaN,
+		allowInf:     cfg.AllowInf,
+		specialsFrac: cfg.SpecialsFrac,
+	})
+}
+
 type floatGen struct {
 	typ        reflect.Type
 	expBits    uint
@@ -90,6 +242,10 @@ type floatGen struct {
 	max        float64
 	minVal     float64
 	maxVal     float64
+
+	allowNaN     bool
+	allowInf     bool
+	specialsFrac float64
 }
 
 func (g *floatGen) String() string {
@@ -98,7 +254,9 @@ func (g *floatGen) String() string {
 		kind = "Float32s"
 	}
 
-	if g.min != g.minVal && g.max != g.maxVal {
+	if g.specialsFrac > 0 {
+		return fmt.Sprintf("%sWithConfig(FloatsConfig{Min: %g, Max: %g, AllowNaN: %v, AllowInf: %v, SpecialsFrac: %g})", kind, g.min, g.max, g.allowNaN, g.allowInf, g.specialsFrac)
+	} else if g.min != g.minVal && g.max != g.maxVal {
 		return fmt.Sprintf("%sRange(%g, %g)", kind, g.min, g.max)
 	} else if g.min != g.minVal {
 		return fmt.Sprintf("%sMin(%g)", kind, g.min)
@@ -114,6 +272,19 @@ func (g *floatGen) type_() reflect.Type {
 }
 
 func (g *floatGen) value(s bitStream) Value {
+	if g.specialsFrac > 0 {
+		if cands := g.specialCandidates(); len(cands) > 0 && flipBiasedCoin(s, g.specialsFrac) {
+			i := s.beginGroup(floatSpecialLabel, false)
+			idx := genIntRange(s, 0, int64(len(cands)-1), true)
+			s.endGroup(i, false)
+
+			if g.typ == float32Type {
+				return float32(cands[idx])
+			}
+			return cands[idx]
+		}
+	}
+
 	f := genFloatRange(s, g.min, g.max, g.expBits, g.signifBits)
 
 	if g.typ == float32Type {
@@ -123,6 +294,34 @@ func (g *floatGen) value(s bitStream) Value {
 	}
 }
 
+// specialCandidates returns the special values that satisfy g's bounds and
+// NaN/Inf policy, preserving the shrink-preference order of the source list.
+func (g *floatGen) specialCandidates() []float64 {
+	all := float64Specials
+	if g.typ == float32Type {
+		all = float32Specials
+	}
+
+	cands := make([]float64, 0, len(all))
+	for _, f := range all {
+		if math.IsNaN(f) {
+			if g.allowNaN {
+				cands = append(cands, f)
+			}
+			continue
+		}
+		if math.IsInf(f, 0) && !g.allowInf {
+			continue
+		}
+		if f < g.min || f > g.max {
+			continue
+		}
+		cands = append(cands, f)
+	}
+
+	return cands
+}
+
 func ufloatFracBits(e int32, signifBits uint) uint {
 	if e <= 0 {
 		return signifBits
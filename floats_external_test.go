@@ -0,0 +1,84 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/flyingmutant/rapid"
+)
+
+func TestFloat64sWithSpecialsRespectsBounds(t *testing.T) {
+	g := Float64sWithSpecials(0, 100, false, false)
+
+	Check(t, func(t *T, f float64) {
+		if math.IsNaN(f) {
+			t.Fatalf("got NaN with allowNaN=false")
+		}
+		if f < 0 || f > 100 {
+			t.Fatalf("got %v outside of [0, 100]", f)
+		}
+	}, g)
+}
+
+func TestFloat64sWithConfigSpecialsFracZeroDisablesSpecials(t *testing.T) {
+	g := Float64sWithConfig(FloatsConfig{
+		Min:          -math.MaxFloat64,
+		Max:          math.MaxFloat64,
+		AllowNaN:     true,
+		AllowInf:     true,
+		SpecialsFrac: 0,
+	})
+
+	Check(t, func(t *T, f float64) {
+		if math.IsNaN(f) {
+			t.Fatalf("got NaN with SpecialsFrac=0")
+		}
+	}, g)
+}
+
+func TestFloat64sAnyCoversMultipleNaNPayloads(t *testing.T) {
+	g := Float64sAny()
+
+	seen := map[uint64]bool{}
+	Check(t, func(t *T, f float64) {
+		if math.IsNaN(f) {
+			seen[math.Float64bits(f)] = true
+		}
+	}, g)
+
+	if len(seen) < 2 {
+		t.Fatalf("got only %d distinct NaN bit pattern(s), want more than one", len(seen))
+	}
+}
+
+func TestFloat32sAnyCoversMultipleNaNPayloads(t *testing.T) {
+	g := Float32sAny()
+
+	seen := map[uint32]bool{}
+	Check(t, func(t *T, f float32) {
+		if math.IsNaN(float64(f)) {
+			seen[math.Float32bits(f)] = true
+		}
+	}, g)
+
+	if len(seen) < 2 {
+		t.Fatalf("got only %d distinct NaN bit pattern(s), want more than one", len(seen))
+	}
+}
+
+func TestFloat32sAnyStaysFinitePrecision(t *testing.T) {
+	g := Float32sAny()
+
+	Check(t, func(t *T, f float32) {
+		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+			return
+		}
+		if float64(f) < -math.MaxFloat32 || float64(f) > math.MaxFloat32 {
+			t.Fatalf("got %v outside of float32 range", f)
+		}
+	}, g)
+}
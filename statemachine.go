@@ -0,0 +1,141 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const maxStateMachineSteps = 50
+
+// State is the user-defined model of a system under test, as tracked across
+// a StateMachine run. It is opaque to rapid; commands type-assert it back to
+// their own concrete state type.
+type State = interface{}
+
+// Command is one possible transition of a StateMachine. Pre (optional)
+// reports whether the command is legal to run at all in the current State;
+// it gates which commands are eligible to be picked next, so it must not
+// depend on anything but State -- a command whose Pre rejects every
+// argument the moment it's picked would otherwise make that step
+// undrawable no matter how many times Gen is retried. Gen draws arguments
+// conditioned on the current model State; Run applies the command to both
+// the model and the system under test and returns the new State together
+// with whatever result should be recorded for reporting; Invariant
+// (optional) is checked against the State returned by Run and should
+// return a non-nil error to fail the property.
+type Command struct {
+	Name      string
+	Gen       func(Data, State) Value
+	Pre       func(State) bool
+	Run       func(State, Value) (State, Value)
+	Invariant func(State) error
+}
+
+// Step is one executed Command in a StateMachine trace, kept around so that
+// a failing Run() can report exactly which command and arguments broke the
+// invariant.
+type Step struct {
+	Command string
+	Args    Value
+	Result  Value
+}
+
+// StateMachine returns a generator of command traces against the model
+// built by initial: on every draw, it runs initial() to get the starting
+// State, then repeatedly picks one of the commands whose Pre currently
+// holds, draws its arguments with Gen, and applies Run to advance the
+// model. Because command selection and argument generation both go
+// through Data.Draw (via Custom), dropping a step and shrinking a step's
+// arguments are both handled by rapid's existing bitstream shrinker --
+// StateMachine adds no shrinking logic of its own. Steps are always
+// executed and recorded in the order they are drawn; nothing here reorders
+// them.
+func StateMachine(initial func() State, commands ...Command) *Generator {
+	assertf(len(commands) > 0, "StateMachine needs at least one command")
+
+	return Custom(func(data Data) []Step {
+		state := initial()
+		steps := make([]Step, 0, maxStateMachineSteps)
+
+		for i := 0; i < maxStateMachineSteps; i++ {
+			if i > 0 && !data.Draw(Booleans(), "more").(bool) {
+				break
+			}
+
+			cmd, args := drawCommand(data, state, commands)
+
+			newState, result := cmd.Run(state, args)
+			steps = append(steps, Step{Command: cmd.Name, Args: args, Result: result})
+
+			if cmd.Invariant != nil {
+				if err := cmd.Invariant(newState); err != nil {
+					panic(invariantViolation{steps: steps, err: err})
+				}
+			}
+
+			state = newState
+		}
+
+		return steps
+	})
+}
+
+func drawCommand(data Data, state State, commands []Command) (Command, Value) {
+	applicable := make([]int, 0, len(commands))
+	for i, cmd := range commands {
+		if cmd.Pre == nil || cmd.Pre(state) {
+			applicable = append(applicable, i)
+		}
+	}
+	assertf(len(applicable) > 0, "StateMachine: no command's Pre holds in the current State")
+
+	idx := applicable[data.Draw(IntsRange(0, len(applicable)-1), "cmd").(int)]
+	cmd := commands[idx]
+
+	args := data.Draw(Custom(func(d Data) Value {
+		return cmd.Gen(d, state)
+	}), cmd.Name)
+
+	return cmd, args
+}
+
+// invariantViolation carries the full step trace leading up to a failing
+// Invariant check, so Run can report not just the offending command but
+// everything that was necessary to drive the model/SUT into that state.
+type invariantViolation struct {
+	steps []Step
+	err   error
+}
+
+func (v invariantViolation) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invariant violated: %v", v.err)
+	for i, s := range v.steps {
+		fmt.Fprintf(&b, "\n  %d: %s(%#v) -> %#v", i, s.Command, s.Args, s.Result)
+	}
+	return b.String()
+}
+
+// Run checks machine (built with StateMachine) as a property: it generates
+// and executes command traces, shrinking any invariant violation to a
+// minimal failing trace, and fails t with the offending command and its
+// arguments.
+func Run(t *testing.T, machine *Generator) {
+	Check(t, func(t *T) {
+		defer func() {
+			if r := recover(); r != nil {
+				if v, ok := r.(invariantViolation); ok {
+					t.Fatalf("%v", v)
+				}
+				panic(r)
+			}
+		}()
+
+		t.Draw(machine, "machine")
+	})
+}
@@ -0,0 +1,101 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/flyingmutant/rapid"
+)
+
+// intQueue is the system under test: a plain slice-backed FIFO queue.
+type intQueue struct {
+	items []int
+}
+
+func (q *intQueue) push(v int) {
+	q.items = append(q.items, v)
+}
+
+func (q *intQueue) pop() int {
+	v := q.items[0]
+	q.items = q.items[1:]
+	return v
+}
+
+// queueModel is the reference model StateMachine checks intQueue against.
+type queueModel struct {
+	sut       *intQueue
+	model     []int
+	lastPopOK bool
+}
+
+func queueCommands() []Command {
+	return []Command{
+		{
+			Name: "Push",
+			Gen: func(data Data, _ State) Value {
+				return data.Draw(Ints(), "v").(int)
+			},
+			Run: func(state State, args Value) (State, Value) {
+				m := state.(*queueModel)
+				v := args.(int)
+				m.sut.push(v)
+				m.model = append(m.model, v)
+				return m, nil
+			},
+		},
+		{
+			Name: "Pop",
+			Pre: func(state State) bool {
+				return len(state.(*queueModel).model) > 0
+			},
+			Gen: func(_ Data, _ State) Value {
+				return nil
+			},
+			Run: func(state State, _ Value) (State, Value) {
+				m := state.(*queueModel)
+				got := m.sut.pop()
+				want := m.model[0]
+				m.model = m.model[1:]
+				m.lastPopOK = got == want
+				return m, got
+			},
+			Invariant: func(state State) error {
+				m := state.(*queueModel)
+				if !m.lastPopOK {
+					return fmt.Errorf("popped value diverged from model")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func TestQueueStateMachine(t *testing.T) {
+	machine := StateMachine(func() State {
+		return &queueModel{sut: &intQueue{}}
+	}, queueCommands()...)
+
+	Run(t, machine)
+}
+
+// TestQueueStateMachineNeverDiscardsOnEmptyPop guards against a regression
+// where drawCommand picked Pop (whose Gen is deterministic and ignores
+// State) before consulting Pre, which discarded roughly half of all traces
+// as invalid the moment an empty queueModel picked Pop on step 0. Pre must
+// gate which command gets picked, not filter the args of one already
+// chosen, so this should run cleanly every time.
+func TestQueueStateMachineNeverDiscardsOnEmptyPop(t *testing.T) {
+	machine := StateMachine(func() State {
+		return &queueModel{sut: &intQueue{}}
+	}, queueCommands()...)
+
+	for i := 0; i < 50; i++ {
+		Run(t, machine)
+	}
+}
+
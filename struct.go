@@ -0,0 +1,397 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var dataType = reflect.TypeOf((*Data)(nil)).Elem()
+
+var registeredTypes = map[reflect.Type]*Generator{}
+
+// RegisterType registers gen as the generator Struct() and StructOf() use
+// for fields of type typ, for types the reflection layer can't infer a
+// generator for on its own (e.g. time.Time, url.URL, big.Int).
+func RegisterType(typ reflect.Type, gen *Generator) {
+	assertf(typ != nil, "type should not be nil")
+	assertf(gen != nil, "generator should not be nil")
+
+	registeredTypes[typ] = gen
+}
+
+// Struct returns a generator for values of typ, built by walking its
+// exported fields and synthesizing a generator per field from its
+// `rapid:"..."` struct tag (e.g. `rapid:"min=0,max=100"`,
+// `rapid:"len=1..64,charset=ascii"`, `rapid:"nonzero"`,
+// `rapid:"oneof=red|green|blue"`). Nested structs, slices, maps and
+// pointers are handled recursively; pointer fields respect `allowNil`
+// like Ptrs(). An unknown tag or unsupported field type is reported here,
+// at generator-construction time, rather than as a draw-time panic.
+//
+// This replaces the common boilerplate of hand-writing a
+// Custom(func(d Data) T { ... }) generator for every struct under test.
+func Struct(typ reflect.Type) *Generator {
+	assertf(typ.Kind() == reflect.Struct, "%v is not a struct", typ)
+
+	fieldGens, err := structFieldGens(typ)
+	assertf(err == nil, "%v", err)
+
+	fn := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{dataType}, []reflect.Type{typ}, false),
+		func(in []reflect.Value) []reflect.Value {
+			data := in[0].Interface().(Data)
+
+			v := reflect.New(typ).Elem()
+			for i, fg := range fieldGens {
+				if fg == nil {
+					continue
+				}
+				val := data.Draw(fg, typ.Field(i).Name)
+				v.Field(i).Set(reflect.ValueOf(val))
+			}
+
+			return []reflect.Value{v}
+		},
+	)
+
+	return Custom(fn.Interface())
+}
+
+// StructOf is Struct() for a Go type parameter, so callers don't have to
+// look up or hold on to a reflect.Type themselves.
+func StructOf[T any]() *Generator {
+	var zero T
+	return Struct(reflect.TypeOf(zero)).Map(func(v T) T { return v })
+}
+
+func structFieldGens(typ reflect.Type) ([]*Generator, error) {
+	gens := make([]*Generator, typ.NumField())
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported field, left at its zero value
+		}
+
+		g, err := fieldGenerator(f)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%s: %w", typ, f.Name, err)
+		}
+		gens[i] = g
+	}
+
+	return gens, nil
+}
+
+func fieldGenerator(f reflect.StructField) (*Generator, error) {
+	if g, ok := registeredTypes[f.Type]; ok {
+		return g, nil
+	}
+
+	c, err := parseFieldTag(f.Tag.Get("rapid"))
+	if err != nil {
+		return nil, err
+	}
+
+	return genForType(f.Type, c)
+}
+
+type fieldConstraints struct {
+	hasMin, hasMax bool
+	min, max       int64
+	hasLen         bool
+	lenMin, lenMax int
+	charset        string
+	oneof          []string
+	nonzero        bool
+	allowNil       bool
+}
+
+func parseFieldTag(tag string) (fieldConstraints, error) {
+	var c fieldConstraints
+	if tag == "" {
+		return c, nil
+	}
+
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		switch kv[0] {
+		case "nonzero":
+			c.nonzero = true
+		case "allowNil":
+			c.allowNil = true
+		case "min":
+			v, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return c, fmt.Errorf("bad min=%q: %w", kv[1], err)
+			}
+			c.hasMin, c.min = true, v
+		case "max":
+			v, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return c, fmt.Errorf("bad max=%q: %w", kv[1], err)
+			}
+			c.hasMax, c.max = true, v
+		case "len":
+			lo, hi, err := parseIntRange(kv[1])
+			if err != nil {
+				return c, fmt.Errorf("bad len=%q: %w", kv[1], err)
+			}
+			c.hasLen, c.lenMin, c.lenMax = true, lo, hi
+		case "charset":
+			c.charset = kv[1]
+		case "oneof":
+			c.oneof = strings.Split(kv[1], "|")
+		default:
+			return c, fmt.Errorf("unknown rapid tag %q", kv[0])
+		}
+	}
+
+	return c, nil
+}
+
+func parseIntRange(s string) (int, int, error) {
+	parts := strings.SplitN(s, "..", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("want lo..hi, got %q", s)
+	}
+
+	lo, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	hi, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lo, hi, nil
+}
+
+func genForType(typ reflect.Type, c fieldConstraints) (*Generator, error) {
+	if g, ok := registeredTypes[typ]; ok {
+		return g, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return Booleans(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return intGen(typ, c)
+	case reflect.Float32, reflect.Float64:
+		return floatGenFor(typ, c), nil
+	case reflect.String:
+		return stringGen(c), nil
+	case reflect.Ptr:
+		elem, err := genForType(typ.Elem(), fieldConstraints{})
+		if err != nil {
+			return nil, err
+		}
+		return Ptrs(elem, c.allowNil), nil
+	case reflect.Slice:
+		elem, err := genForType(typ.Elem(), fieldConstraints{})
+		if err != nil {
+			return nil, err
+		}
+		return sliceGen(typ, elem, c), nil
+	case reflect.Map:
+		keyGen, err := genForType(typ.Key(), fieldConstraints{})
+		if err != nil {
+			return nil, err
+		}
+		valGen, err := genForType(typ.Elem(), fieldConstraints{})
+		if err != nil {
+			return nil, err
+		}
+		return mapGen(typ, keyGen, valGen, c), nil
+	case reflect.Struct:
+		return Struct(typ), nil
+	default:
+		return nil, fmt.Errorf("unsupported field type %v", typ)
+	}
+}
+
+// intKindRange returns the representable [min, max] for k, honoring its
+// actual bit width rather than a one-size-fits-all approximation -- a
+// rapid:"max=..." tag on an int8 field needs to be checked against -128..127,
+// not some wider default, or out-of-range values would quietly wrap when
+// converted back to the field's type. reflect.Uint64 and reflect.Uint are
+// capped at math.MaxInt64 because IntsRange itself works in int64.
+func intKindRange(k reflect.Kind) (int64, int64) {
+	switch k {
+	case reflect.Int8:
+		return math.MinInt8, math.MaxInt8
+	case reflect.Int16:
+		return math.MinInt16, math.MaxInt16
+	case reflect.Int32:
+		return math.MinInt32, math.MaxInt32
+	case reflect.Int, reflect.Int64:
+		return math.MinInt64, math.MaxInt64
+	case reflect.Uint8:
+		return 0, math.MaxUint8
+	case reflect.Uint16:
+		return 0, math.MaxUint16
+	case reflect.Uint32:
+		return 0, math.MaxUint32
+	case reflect.Uint, reflect.Uint64:
+		return 0, math.MaxInt64
+	default:
+		panic(fmt.Sprintf("intKindRange: not an integer kind: %v", k))
+	}
+}
+
+func intGen(typ reflect.Type, c fieldConstraints) (*Generator, error) {
+	defMin, defMax := intKindRange(typ.Kind())
+	min, max := defMin, defMax
+	if c.hasMin {
+		if c.min < defMin || c.min > defMax {
+			return nil, fmt.Errorf("min=%d is out of range for %v", c.min, typ)
+		}
+		min = c.min
+	}
+	if c.hasMax {
+		if c.max < defMin || c.max > defMax {
+			return nil, fmt.Errorf("max=%d is out of range for %v", c.max, typ)
+		}
+		max = c.max
+	}
+	if min > max {
+		return nil, fmt.Errorf("min=%d is greater than max=%d for %v", min, max, typ)
+	}
+
+	fn := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{dataType}, []reflect.Type{typ}, false),
+		func(in []reflect.Value) []reflect.Value {
+			data := in[0].Interface().(Data)
+			n := data.Draw(IntsRange(int(min), int(max)), "")
+			return []reflect.Value{reflect.ValueOf(n).Convert(typ)}
+		},
+	)
+
+	return Custom(fn.Interface()), nil
+}
+
+func floatGenFor(typ reflect.Type, c fieldConstraints) *Generator {
+	if typ == float32Type {
+		if c.nonzero {
+			return Float32s().Filter(func(f float32) bool { return f != 0 })
+		}
+		return Float32s()
+	}
+
+	if c.nonzero {
+		return Float64s().Filter(func(f float64) bool { return f != 0 })
+	}
+	return Float64s()
+}
+
+func stringGen(c fieldConstraints) *Generator {
+	lenMin, lenMax := 0, 64
+	if c.hasLen {
+		lenMin, lenMax = c.lenMin, c.lenMax
+	}
+
+	if len(c.oneof) > 0 {
+		vals := make([]string, len(c.oneof))
+		copy(vals, c.oneof)
+		return SampledFrom(vals)
+	}
+
+	alphabet := charsetRunes(c.charset)
+
+	return Custom(func(data Data) string {
+		n := data.Draw(IntsRange(lenMin, lenMax), "len").(int)
+
+		runes := make([]rune, n)
+		for i := range runes {
+			idx := data.Draw(IntsRange(0, len(alphabet)-1), "rune").(int)
+			runes[i] = alphabet[idx]
+		}
+
+		return string(runes)
+	})
+}
+
+func charsetRunes(name string) []rune {
+	switch name {
+	case "alpha":
+		return appendRuneRange(appendRuneRange(nil, 'a', 'z'), 'A', 'Z')
+	case "alnum":
+		return appendRuneRange(appendRuneRange(appendRuneRange(nil, 'a', 'z'), 'A', 'Z'), '0', '9')
+	case "digit":
+		return appendRuneRange(nil, '0', '9')
+	default: // "ascii" and unrecognized charsets fall back to printable ASCII
+		return appendRuneRange(nil, 0x20, 0x7e)
+	}
+}
+
+func appendRuneRange(rs []rune, lo rune, hi rune) []rune {
+	for r := lo; r <= hi; r++ {
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+func sliceGen(typ reflect.Type, elemGen *Generator, c fieldConstraints) *Generator {
+	lenMin, lenMax := 0, 16
+	if c.hasLen {
+		lenMin, lenMax = c.lenMin, c.lenMax
+	}
+
+	fn := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{dataType}, []reflect.Type{typ}, false),
+		func(in []reflect.Value) []reflect.Value {
+			data := in[0].Interface().(Data)
+
+			n := data.Draw(IntsRange(lenMin, lenMax), "len").(int)
+			v := reflect.MakeSlice(typ, n, n)
+			for i := 0; i < n; i++ {
+				elem := data.Draw(elemGen, "")
+				v.Index(i).Set(reflect.ValueOf(elem))
+			}
+
+			return []reflect.Value{v}
+		},
+	)
+
+	return Custom(fn.Interface())
+}
+
+func mapGen(typ reflect.Type, keyGen *Generator, valGen *Generator, c fieldConstraints) *Generator {
+	lenMin, lenMax := 0, 16
+	if c.hasLen {
+		lenMin, lenMax = c.lenMin, c.lenMax
+	}
+
+	fn := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{dataType}, []reflect.Type{typ}, false),
+		func(in []reflect.Value) []reflect.Value {
+			data := in[0].Interface().(Data)
+
+			n := data.Draw(IntsRange(lenMin, lenMax), "len").(int)
+			v := reflect.MakeMapWithSize(typ, n)
+			for i := 0; i < n; i++ {
+				key := data.Draw(keyGen, "")
+				val := data.Draw(valGen, "")
+				v.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(val))
+			}
+
+			return []reflect.Value{v}
+		},
+	)
+
+	return Custom(fn.Interface())
+}
@@ -0,0 +1,107 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/flyingmutant/rapid"
+)
+
+type taggedStruct struct {
+	Age   int     `rapid:"min=0,max=130"`
+	Name  string  `rapid:"len=1..16,charset=alpha"`
+	Color string  `rapid:"oneof=red|green|blue"`
+	Pct   float64 `rapid:"nonzero"`
+}
+
+func TestStructRespectsTags(t *testing.T) {
+	g := Struct(reflect.TypeOf(taggedStruct{}))
+
+	Check(t, func(t *T, v taggedStruct) {
+		if v.Age < 0 || v.Age > 130 {
+			t.Fatalf("got Age %v outside of [0, 130]", v.Age)
+		}
+		if len(v.Name) < 1 || len(v.Name) > 16 {
+			t.Fatalf("got Name %q with bad length", v.Name)
+		}
+		if v.Color != "red" && v.Color != "green" && v.Color != "blue" {
+			t.Fatalf("got impossible Color %q", v.Color)
+		}
+		if v.Pct == 0 {
+			t.Fatalf("got zero Pct despite nonzero tag")
+		}
+	}, g)
+}
+
+func TestStructOfUnknownTagErrors(t *testing.T) {
+	type badStruct struct {
+		X int `rapid:"bogus=1"`
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected Struct() to panic on unknown tag")
+		}
+	}()
+
+	Struct(reflect.TypeOf(badStruct{}))
+}
+
+func TestStructRespectsNarrowIntKindBounds(t *testing.T) {
+	type narrowStruct struct {
+		I8  int8
+		U8  uint8
+		I16 int16
+	}
+
+	g := Struct(reflect.TypeOf(narrowStruct{}))
+
+	Check(t, func(t *T, v narrowStruct) {
+		// the field types already bound the values, so a wrapped value would
+		// still look valid here; this only checks that Struct() builds and
+		// draws int8/uint8/int16 fields without panicking
+		_ = v
+	}, g)
+}
+
+func TestStructOfOutOfRangeTagErrors(t *testing.T) {
+	type badStruct struct {
+		X int8 `rapid:"max=1000"`
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected Struct() to panic on out-of-range max tag")
+		}
+	}()
+
+	Struct(reflect.TypeOf(badStruct{}))
+}
+
+func TestStructOfOutOfRangeUnsignedTagErrors(t *testing.T) {
+	type badStruct struct {
+		X uint8 `rapid:"min=-1"`
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected Struct() to panic on out-of-range min tag")
+		}
+	}()
+
+	Struct(reflect.TypeOf(badStruct{}))
+}
+
+func TestStructOfGeneric(t *testing.T) {
+	g := StructOf[taggedStruct]()
+
+	Check(t, func(t *T, v taggedStruct) {
+		if v.Color != "red" && v.Color != "green" && v.Color != "blue" {
+			t.Fatalf("got impossible Color %q", v.Color)
+		}
+	}, g)
+}
@@ -0,0 +1,192 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+const (
+	targetedSeedCases   = 20
+	targetedMutateCases = 20
+	targetedCorpusSize  = 32
+	targetedInitialTemp = 1.0
+	targetedCoolingRate = 0.99
+)
+
+// targetedCorpusEntry is one (arguments, score) pair found during the
+// simulated-annealing search in seedTargetedCorpus. Entries are argument
+// tuples rather than raw bitstream bytes, so mutation works at
+// generator-call granularity (redraw one argument) instead of flipping
+// bits inside a labeled group such as floatExpLabel/floatSignifLabel -- a
+// coarser approximation of structure-aware mutation, but one that needs no
+// access to the shrinker's internal encoding.
+type targetedCorpusEntry struct {
+	args  []Value
+	score float64
+}
+
+// CheckTargeted is like Check, but additionally searches for inputs that
+// maximize target, in the spirit of targeted property-based testing and
+// coverage-guided fuzzing. Before checking anything, it runs an entirely
+// separate simulated-annealing search -- drawing fresh argument tuples from
+// gens, then repeatedly mutating the most recently accepted one and keeping
+// the mutation if it scores at least as high or, with probability
+// exp((new-old)/T) for a cooling temperature T, even if it scores lower -- to
+// build a corpus of high-scoring argument tuples. Check() then runs as usual, except each
+// generator in gens is replaced by one that sometimes samples from that
+// fixed corpus instead of drawing fresh. Because the corpus is computed
+// once, up front, and never mutated while Check is running, the checked
+// property remains a pure function of its own draws: replaying the same
+// bitstream bytes during shrinking always takes the same corpus-or-fresh
+// branch and produces the same arguments, which is what the shrinker
+// requires. prop takes a *T followed by one argument per generator in gens,
+// exactly like Check's prop; target takes the same arguments without the *T,
+// is called only during the corpus search, and must return a float64.
+//
+// The corpus search itself draws from a single bitstream obtained from
+// createRandomBitStream, the same general-purpose random source the
+// engine's own tests use, rather than from -rapid.seed and the engine's
+// replay machinery, so the corpus a given run finds is not reproducible
+// across runs the way a failing Check case is. Nor is the corpus persisted
+// anywhere (e.g. for go test -fuzz style corpus reuse across runs): it is
+// rebuilt from scratch, in memory, every time CheckTargeted is called.
+func CheckTargeted(t *testing.T, prop interface{}, target interface{}, gens ...*Generator) {
+	propVal := reflect.ValueOf(prop)
+	targetVal := reflect.ValueOf(target)
+
+	corpus := seedTargetedCorpus(t, gens, targetVal)
+
+	seeded := make([]*Generator, len(gens))
+	for i, g := range gens {
+		seeded[i] = withTargetedCorpus(g, corpus, i)
+	}
+
+	Check(t, func(t *T) {
+		args := make([]Value, len(seeded))
+		for i, g := range seeded {
+			args[i] = t.Draw(g, "")
+		}
+		callProp(t, propVal, args)
+	})
+}
+
+// seedTargetedCorpus runs the simulated-annealing search described by
+// CheckTargeted entirely outside of Check, so that the resulting corpus is
+// fixed before the property under test ever runs. The whole search -- seed
+// draws and mutations alike -- is threaded through a single bitstream, the
+// same pattern the rest of the package uses for one logical draw, rather
+// than opening a fresh throwaway bitstream per draw.
+func seedTargetedCorpus(t *testing.T, gens []*Generator, targetVal reflect.Value) []targetedCorpusEntry {
+	s := createRandomBitStream(t)
+
+	var corpus []targetedCorpusEntry
+
+	for i := 0; i < targetedSeedCases; i++ {
+		args, ok := drawAllTargeted(s, gens)
+		if !ok {
+			continue
+		}
+		corpus = appendCorpusCapped(corpus, targetedCorpusEntry{args: args, score: callTarget(targetVal, args)})
+	}
+
+	temp := targetedInitialTemp
+	for i := 0; i < targetedMutateCases && len(corpus) > 0; i++ {
+		base := corpus[len(corpus)-1]
+		args := make([]Value, len(base.args))
+		copy(args, base.args)
+
+		idx := int(genIntRange(s, 0, int64(len(gens)-1), true))
+		v, err := recoverValue(gens[idx], s)
+		if err != nil {
+			continue
+		}
+		args[idx] = v
+
+		score := callTarget(targetVal, args)
+		if score >= base.score || acceptAnnealed(s, score, base.score, temp) {
+			corpus = appendCorpusCapped(corpus, targetedCorpusEntry{args: args, score: score})
+		}
+		temp *= targetedCoolingRate
+	}
+
+	return corpus
+}
+
+func drawAllTargeted(s bitStream, gens []*Generator) ([]Value, bool) {
+	args := make([]Value, len(gens))
+	for i, g := range gens {
+		v, err := recoverValue(g, s)
+		if err != nil {
+			return nil, false
+		}
+		args[i] = v
+	}
+
+	return args, true
+}
+
+func acceptAnnealed(s bitStream, newScore float64, oldScore float64, temp float64) bool {
+	if temp <= 0 {
+		return false
+	}
+
+	p := math.Exp((newScore - oldScore) / temp)
+	if p > 1 {
+		p = 1
+	}
+
+	return flipBiasedCoin(s, p)
+}
+
+func appendCorpusCapped(corpus []targetedCorpusEntry, next targetedCorpusEntry) []targetedCorpusEntry {
+	corpus = append(corpus, next)
+	if len(corpus) <= targetedCorpusSize {
+		return corpus
+	}
+
+	worst := 0
+	for i, e := range corpus {
+		if e.score < corpus[worst].score {
+			worst = i
+		}
+	}
+	return append(corpus[:worst], corpus[worst+1:]...)
+}
+
+// withTargetedCorpus returns a generator that sometimes replays one of the
+// corpus's values for slot instead of drawing fresh from g.
+func withTargetedCorpus(g *Generator, corpus []targetedCorpusEntry, slot int) *Generator {
+	if len(corpus) == 0 {
+		return g
+	}
+
+	elemType := reflect.TypeOf(corpus[0].args[slot])
+	vals := reflect.MakeSlice(reflect.SliceOf(elemType), len(corpus), len(corpus))
+	for i, e := range corpus {
+		vals.Index(i).Set(reflect.ValueOf(e.args[slot]))
+	}
+
+	return OneOf(g, SampledFrom(vals.Interface()))
+}
+
+func callProp(t *T, propVal reflect.Value, args []Value) {
+	in := make([]reflect.Value, 0, len(args)+1)
+	in = append(in, reflect.ValueOf(t))
+	for _, a := range args {
+		in = append(in, reflect.ValueOf(a))
+	}
+	propVal.Call(in)
+}
+
+func callTarget(targetVal reflect.Value, args []Value) float64 {
+	in := make([]reflect.Value, len(args))
+	for i, a := range args {
+		in[i] = reflect.ValueOf(a)
+	}
+	return targetVal.Call(in)[0].Float()
+}
@@ -0,0 +1,27 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package rapid_test
+
+import (
+	"testing"
+
+	. "github.com/flyingmutant/rapid"
+)
+
+func TestCheckTargetedMaximizesSum(t *testing.T) {
+	var best int
+
+	CheckTargeted(t, func(t *T, a int, b int) {
+		if a+b > best {
+			best = a + b
+		}
+	}, func(a int, b int) float64 {
+		return float64(a + b)
+	}, IntsRange(0, 1000), IntsRange(0, 1000))
+
+	if best < 1000 {
+		t.Fatalf("targeted search never got close to the maximum, best sum was %v", best)
+	}
+}